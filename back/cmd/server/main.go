@@ -0,0 +1,39 @@
+// Command server is the entrypoint for the instant notification service: it
+// loads configuration, opens the SQLite database, and starts the Gin router.
+//
+// @title        Instant Notification Service API
+// @version      1.0
+// @description  Form submissions, topic pub/sub streaming, and pluggable notification dispatch.
+// @BasePath     /
+package main
+
+import (
+	"log"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/api"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/config"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+
+	_ "github.com/K2adir/instant-notification-service-GO-React/back/docs"
+)
+
+func main() {
+	cfg := config.Load()
+
+	conn, err := db.Open(cfg.SQLitePath)
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
+	}
+	defer conn.Close()
+
+	store := db.NewSubmissionStore(conn)
+	hub := broadcaster.NewHub(cfg.TopicBufferSize, cfg.TopicBufferAge)
+
+	r := api.NewRouter(cfg, conn, store, hub)
+
+	log.Println("Server running on", cfg.Addr())
+	if err := r.Run(cfg.Addr()); err != nil {
+		log.Fatal("Failed to start server:", err)
+	}
+}
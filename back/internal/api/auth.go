@@ -0,0 +1,266 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recognized auth token scopes.
+const (
+	scopePublish   = "publish"
+	scopeSubscribe = "subscribe"
+	scopeAdmin     = "admin"
+)
+
+// AuthToken is the persisted record backing the auth_tokens table. Raw tokens
+// are never stored; only their SHA-256 hash is, so a DB leak doesn't leak
+// credentials.
+type AuthToken struct {
+	ID         int64      `json:"id"`
+	Label      string     `json:"label"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+}
+
+// hashToken returns the hex-encoded SHA-256 digest of a raw bearer token.
+func hashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateToken returns a new random bearer token, prefixed so tokens are
+// recognizable in logs and config without decoding them.
+func generateToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "nts_" + hex.EncodeToString(buf), nil
+}
+
+func hasScope(scopes []string, want string) bool {
+	for _, s := range scopes {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+func joinScopes(scopes []string) string { return strings.Join(scopes, ",") }
+
+func splitScopes(csv string) []string {
+	if csv == "" {
+		return nil
+	}
+	return strings.Split(csv, ",")
+}
+
+// extractBearerToken pulls the raw token from the Authorization header or,
+// failing that, the ?auth= query param (needed since browser EventSource
+// can't set custom headers).
+func extractBearerToken(c *gin.Context) string {
+	if h := c.GetHeader("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		return strings.TrimPrefix(h, "Bearer ")
+	}
+	return c.Query("auth")
+}
+
+const authTokenContextKey = "authToken"
+
+// authContext reports the token attached to the request by requireScope, if any.
+func authContext(c *gin.Context) (*AuthToken, bool) {
+	v, ok := c.Get(authTokenContextKey)
+	if !ok {
+		return nil, false
+	}
+	tok, ok := v.(*AuthToken)
+	return tok, ok
+}
+
+// lookupToken resolves a raw bearer token to its stored record and bumps last_used_at.
+func lookupToken(conn *sql.DB, raw string) (*AuthToken, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	hash := hashToken(raw)
+	var tok AuthToken
+	var scopesCSV string
+	var lastUsed sql.NullTime
+	err := conn.QueryRow(
+		"SELECT id, label, scopes, created_at, last_used_at FROM auth_tokens WHERE token_hash = ?",
+		hash,
+	).Scan(&tok.ID, &tok.Label, &scopesCSV, &tok.CreatedAt, &lastUsed)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tok.Scopes = splitScopes(scopesCSV)
+	if lastUsed.Valid {
+		tok.LastUsedAt = &lastUsed.Time
+	}
+	_, _ = conn.Exec("UPDATE auth_tokens SET last_used_at = ? WHERE id = ?", time.Now(), tok.ID)
+	return &tok, nil
+}
+
+// requireScope rejects requests whose token is missing or lacks the given scope.
+func requireScope(conn *sql.DB, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := extractBearerToken(c)
+		if raw == "" {
+			c.AbortWithStatusJSON(401, gin.H{"error": true, "message": "missing bearer token", "code": 401})
+			return
+		}
+		tok, err := lookupToken(conn, raw)
+		if err != nil {
+			c.AbortWithStatusJSON(500, gin.H{"error": true, "message": "failed to verify token", "code": 500})
+			return
+		}
+		if tok == nil || !hasScope(tok.Scopes, scope) {
+			c.AbortWithStatusJSON(403, gin.H{"error": true, "message": "token missing required scope: " + scope, "code": 403})
+			return
+		}
+		c.Set(authTokenContextKey, tok)
+		c.Next()
+	}
+}
+
+// bootstrapAdminToken ensures rawToken (if set) exists in auth_tokens with
+// the admin scope, so a fresh deployment has a way to mint further tokens
+// through POST /api/auth/tokens without direct database access. token_hash is
+// unique, so this is safe to call on every boot: once the row exists the
+// insert is a no-op.
+func bootstrapAdminToken(conn *sql.DB, rawToken string) error {
+	if rawToken == "" {
+		return nil
+	}
+	_, err := conn.Exec(
+		"INSERT OR IGNORE INTO auth_tokens (token_hash, label, scopes) VALUES (?, ?, ?)",
+		hashToken(rawToken), "bootstrap", joinScopes([]string{scopeAdmin}),
+	)
+	return err
+}
+
+// createTokenHandler handles POST /api/auth/tokens (admin-only). It returns
+// the raw token exactly once; only its hash is persisted.
+//
+// @Summary      Create a bearer token
+// @Description  Requires a token with the admin scope.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        token  body  object{label=string,scopes=[]string}  true  "Label and scopes (publish, subscribe, admin) for the new token"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /api/auth/tokens [post]
+func createTokenHandler(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			var body struct {
+				Label  string   `json:"label"`
+				Scopes []string `json:"scopes"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				return nil, errBadRequest("invalid payload")
+			}
+			for _, s := range body.Scopes {
+				if s != scopePublish && s != scopeSubscribe && s != scopeAdmin {
+					return nil, errBadRequest("unknown scope: " + s)
+				}
+			}
+			if len(body.Scopes) == 0 {
+				return nil, errBadRequest("at least one scope is required")
+			}
+
+			raw, err := generateToken()
+			if err != nil {
+				return nil, errInternal("failed to generate token")
+			}
+
+			res, err := conn.Exec(
+				"INSERT INTO auth_tokens (token_hash, label, scopes) VALUES (?, ?, ?)",
+				hashToken(raw), body.Label, joinScopes(body.Scopes),
+			)
+			if err != nil {
+				return nil, errInternal("failed to save token")
+			}
+			id, _ := res.LastInsertId()
+			return gin.H{"id": id, "token": raw, "label": body.Label, "scopes": body.Scopes}, nil
+		})
+	}
+}
+
+// listTokensHandler handles GET /api/auth/tokens (admin-only). Raw tokens are
+// never returned, only metadata.
+//
+// @Summary      List bearer tokens
+// @Description  Requires a token with the admin scope. Raw tokens are never returned.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {array}  AuthToken
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /api/auth/tokens [get]
+func listTokensHandler(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			rows, err := conn.Query("SELECT id, label, scopes, created_at, last_used_at FROM auth_tokens ORDER BY created_at DESC")
+			if err != nil {
+				return nil, errInternal("failed to list tokens")
+			}
+			defer rows.Close()
+
+			out := []AuthToken{}
+			for rows.Next() {
+				var tok AuthToken
+				var scopesCSV string
+				var lastUsed sql.NullTime
+				if err := rows.Scan(&tok.ID, &tok.Label, &scopesCSV, &tok.CreatedAt, &lastUsed); err != nil {
+					return nil, errInternal("failed to read tokens")
+				}
+				tok.Scopes = splitScopes(scopesCSV)
+				if lastUsed.Valid {
+					tok.LastUsedAt = &lastUsed.Time
+				}
+				out = append(out, tok)
+			}
+			return out, nil
+		})
+	}
+}
+
+// deleteTokenHandler handles DELETE /api/auth/tokens/:id (admin-only).
+//
+// @Summary      Revoke a bearer token
+// @Description  Requires a token with the admin scope.
+// @Tags         auth
+// @Produce      json
+// @Param        id  path  int  true  "Token ID"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /api/auth/tokens/{id} [delete]
+func deleteTokenHandler(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil || id <= 0 {
+				return nil, errBadRequest("invalid id")
+			}
+			if _, err := conn.Exec("DELETE FROM auth_tokens WHERE id = ?", id); err != nil {
+				return nil, errInternal("failed to delete token")
+			}
+			return gin.H{"ok": true}, nil
+		})
+	}
+}
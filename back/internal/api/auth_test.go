@@ -0,0 +1,164 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestHashTokenIsDeterministic(t *testing.T) {
+	if hashToken("abc") != hashToken("abc") {
+		t.Fatal("expected hashToken to be deterministic for the same input")
+	}
+	if hashToken("abc") == hashToken("abd") {
+		t.Fatal("expected different inputs to hash differently")
+	}
+}
+
+func TestGenerateTokenIsPrefixedAndUnique(t *testing.T) {
+	tok1, err := generateToken()
+	if err != nil {
+		t.Fatalf("generateToken: %v", err)
+	}
+	tok2, err := generateToken()
+	if err != nil {
+		t.Fatalf("generateToken: %v", err)
+	}
+	if tok1 == tok2 {
+		t.Fatal("expected two generated tokens to differ")
+	}
+	const prefix = "nts_"
+	if len(tok1) <= len(prefix) || tok1[:len(prefix)] != prefix {
+		t.Fatalf("expected token to start with %q, got %q", prefix, tok1)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	scopes := []string{scopePublish, scopeSubscribe}
+	if !hasScope(scopes, scopePublish) {
+		t.Fatal("expected hasScope to find a scope present in the list")
+	}
+	if hasScope(scopes, scopeAdmin) {
+		t.Fatal("expected hasScope to report false for an absent scope")
+	}
+}
+
+func TestJoinAndSplitScopesRoundTrip(t *testing.T) {
+	scopes := []string{scopePublish, scopeAdmin}
+	csv := joinScopes(scopes)
+	got := splitScopes(csv)
+	if len(got) != len(scopes) || got[0] != scopes[0] || got[1] != scopes[1] {
+		t.Fatalf("expected round-trip to preserve scopes, got %v", got)
+	}
+	if splitScopes("") != nil {
+		t.Fatal("expected splitScopes(\"\") to return nil")
+	}
+}
+
+func TestRequireScopeRejectsMissingAndWrongScopeTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := newTestConn(t)
+
+	raw, err := generateToken()
+	if err != nil {
+		t.Fatalf("generateToken: %v", err)
+	}
+	if _, err := conn.Exec(
+		"INSERT INTO auth_tokens (token_hash, label, scopes) VALUES (?, ?, ?)",
+		hashToken(raw), "test", joinScopes([]string{scopePublish}),
+	); err != nil {
+		t.Fatalf("insert token: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/admin", requireScope(conn, scopeAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	noAuth := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, noAuth)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 with no token, got %d", w.Code)
+	}
+
+	wrongScope := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	wrongScope.Header.Set("Authorization", "Bearer "+raw)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, wrongScope)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for token missing admin scope, got %d", w.Code)
+	}
+}
+
+func TestRequireScopeAllowsMatchingScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := newTestConn(t)
+
+	raw, err := generateToken()
+	if err != nil {
+		t.Fatalf("generateToken: %v", err)
+	}
+	if _, err := conn.Exec(
+		"INSERT INTO auth_tokens (token_hash, label, scopes) VALUES (?, ?, ?)",
+		hashToken(raw), "test", joinScopes([]string{scopeAdmin}),
+	); err != nil {
+		t.Fatalf("insert token: %v", err)
+	}
+
+	r := gin.New()
+	r.GET("/admin", requireScope(conn, scopeAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+raw)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for token with admin scope, got %d", w.Code)
+	}
+}
+
+func TestBootstrapAdminTokenIsIdempotent(t *testing.T) {
+	conn := newTestConn(t)
+
+	if err := bootstrapAdminToken(conn, "seed-token"); err != nil {
+		t.Fatalf("bootstrapAdminToken: %v", err)
+	}
+	if err := bootstrapAdminToken(conn, "seed-token"); err != nil {
+		t.Fatalf("bootstrapAdminToken (second call): %v", err)
+	}
+
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM auth_tokens WHERE token_hash = ?", hashToken("seed-token")).Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected exactly one row for the bootstrap token, got %d", count)
+	}
+
+	tok, err := lookupToken(conn, "seed-token")
+	if err != nil {
+		t.Fatalf("lookupToken: %v", err)
+	}
+	if tok == nil || !hasScope(tok.Scopes, scopeAdmin) {
+		t.Fatalf("expected bootstrapped token to carry the admin scope, got %+v", tok)
+	}
+}
+
+func TestBootstrapAdminTokenNoopWhenEmpty(t *testing.T) {
+	conn := newTestConn(t)
+	if err := bootstrapAdminToken(conn, ""); err != nil {
+		t.Fatalf("bootstrapAdminToken: %v", err)
+	}
+	var count int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM auth_tokens").Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected no tokens to be created for an empty bootstrap token, got %d", count)
+	}
+}
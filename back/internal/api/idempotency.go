@@ -0,0 +1,152 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// idempotencyTTL bounds how long a cached (key, route, response) tuple is
+// honored before the key can be reused for a new request.
+const idempotencyTTL = 24 * time.Hour
+
+const idempotencyHeader = "Idempotency-Key"
+
+// responseRecorder wraps a gin.ResponseWriter to capture the status and body
+// written by the handler, so idempotent can cache it without double-writing
+// to the real connection.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *responseRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// idempotent caches the response of the wrapped handler keyed by the
+// Idempotency-Key header plus the route, replaying it verbatim on retry with
+// the same request body. A retry with the same key but a different body gets
+// a 409 Conflict instead of re-running the handler. Requests without the
+// header are unaffected.
+func idempotent(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": true, "message": "failed to read body", "code": 400})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		route := c.Request.URL.Path
+		hash := requestHash(body)
+
+		cached, err := lookupIdempotencyRecord(conn, key, route)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": true, "message": "failed to check idempotency key", "code": 500})
+			return
+		}
+		if cached != nil {
+			if cached.RequestHash != hash {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": true, "message": "Idempotency-Key already used with a different request body", "code": 409})
+				return
+			}
+			c.Data(cached.Status, gin.MIMEJSON, []byte(cached.Body))
+			c.Abort()
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = rec
+		c.Next()
+
+		if c.IsAborted() || rec.status >= 500 {
+			return
+		}
+		if err := saveIdempotencyRecord(conn, key, route, hash, rec.status, rec.body.Bytes()); err != nil {
+			log.Println("idempotent: failed to save record:", err)
+		}
+	}
+}
+
+// requestHash returns the hex-encoded SHA-256 digest of a request body, used
+// to distinguish a retried request from a key reused with a different body.
+func requestHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+type idempotencyRecord struct {
+	RequestHash string
+	Status      int
+	Body        string
+}
+
+func lookupIdempotencyRecord(conn *sql.DB, key, route string) (*idempotencyRecord, error) {
+	cutoff := time.Now().Add(-idempotencyTTL)
+	var rec idempotencyRecord
+	err := conn.QueryRow(
+		"SELECT request_hash, status, body FROM idempotency WHERE key = ? AND route = ? AND created_at > ?",
+		key, route, cutoff,
+	).Scan(&rec.RequestHash, &rec.Status, &rec.Body)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func saveIdempotencyRecord(conn *sql.DB, key, route, hash string, status int, body []byte) error {
+	_, err := conn.Exec(
+		"INSERT OR REPLACE INTO idempotency (key, route, request_hash, status, body, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		key, route, hash, status, string(body), time.Now(),
+	)
+	return err
+}
+
+// sweepIdempotencyKeys deletes idempotency records older than idempotencyTTL.
+// Run on a ticker from startIdempotencySweeper.
+func sweepIdempotencyKeys(conn *sql.DB) {
+	cutoff := time.Now().Add(-idempotencyTTL)
+	if _, err := conn.Exec("DELETE FROM idempotency WHERE created_at <= ?", cutoff); err != nil {
+		log.Println("idempotent: failed to sweep expired keys:", err)
+	}
+}
+
+// startIdempotencySweeper runs sweepIdempotencyKeys on an interval until the process exits.
+func startIdempotencySweeper(conn *sql.DB, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sweepIdempotencyKeys(conn)
+		}
+	}()
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+func newTestConn(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestIdempotentReplaysSameKeyAndBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := newTestConn(t)
+
+	calls := 0
+	r := gin.New()
+	r.POST("/echo", idempotent(conn), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	do := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Idempotency-Key", "key-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do()
+	second := do()
+
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, ran %d times", calls)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatalf("expected replayed body to match first response: %q != %q", first.Body.String(), second.Body.String())
+	}
+	if second.Code != http.StatusOK {
+		t.Fatalf("expected replayed status 200, got %d", second.Code)
+	}
+}
+
+func TestIdempotentConflictsOnDifferentBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := newTestConn(t)
+
+	r := gin.New()
+	r.POST("/echo", idempotent(conn), func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	req1 := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":1}`))
+	req1.Header.Set("Idempotency-Key", "key-2")
+	w1 := httptest.NewRecorder()
+	r.ServeHTTP(w1, req1)
+
+	req2 := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":2}`))
+	req2.Header.Set("Idempotency-Key", "key-2")
+	w2 := httptest.NewRecorder()
+	r.ServeHTTP(w2, req2)
+
+	if w2.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for reused key with different body, got %d: %s", w2.Code, w2.Body.String())
+	}
+}
+
+// TestIdempotentKeysOnConcreteResourcePath guards against caching on the
+// route pattern (e.g. "/items/:id"), which would make the same Idempotency-Key
+// + body short-circuit requests for two different resource IDs.
+func TestIdempotentKeysOnConcreteResourcePath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	conn := newTestConn(t)
+
+	var seen []string
+	r := gin.New()
+	r.POST("/items/:id", idempotent(conn), func(c *gin.Context) {
+		seen = append(seen, c.Param("id"))
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+
+	do := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/items/"+id, strings.NewReader(`{"a":1}`))
+		req.Header.Set("Idempotency-Key", "shared-key")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := do("1")
+	second := do("2")
+
+	if len(seen) != 2 {
+		t.Fatalf("expected the handler to run for both resource IDs, ran for %v", seen)
+	}
+	if first.Body.String() == second.Body.String() {
+		t.Fatalf("expected different resources to get different responses, both were %q", first.Body.String())
+	}
+}
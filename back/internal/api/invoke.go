@@ -0,0 +1,43 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPError is the uniform error shape returned by Invoke-wrapped handlers.
+type HTTPError struct {
+	Code    int
+	Message string
+}
+
+func errBadRequest(msg string) *HTTPError   { return &HTTPError{Code: 400, Message: msg} }
+func errUnauthorized(msg string) *HTTPError { return &HTTPError{Code: 401, Message: msg} }
+func errForbidden(msg string) *HTTPError    { return &HTTPError{Code: 403, Message: msg} }
+func errConflict(msg string) *HTTPError     { return &HTTPError{Code: 409, Message: msg} }
+func errTooManyReqs(msg string) *HTTPError  { return &HTTPError{Code: 429, Message: msg} }
+func errInternal(msg string) *HTTPError     { return &HTTPError{Code: 500, Message: msg} }
+
+// Invoke runs fn and writes its result as JSON, centralizing the
+// {"error": true, "message": ..., "code": ...} shape every handler used to
+// build by hand. Handlers that stream (SSE/WS) don't fit this shape and call
+// c.JSON/c.Writer directly instead.
+func Invoke(c *gin.Context, fn func() (interface{}, *HTTPError)) {
+	result, httpErr := fn()
+	if httpErr != nil {
+		c.JSON(httpErr.Code, gin.H{"error": true, "message": httpErr.Message, "code": httpErr.Code})
+		return
+	}
+	c.JSON(200, result)
+}
+
+// parseID parses a positive int64 path param, the form every :id route in
+// this package expects.
+func parseID(raw string) (int64, error) {
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, strconv.ErrSyntax
+	}
+	return id, nil
+}
@@ -0,0 +1,356 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-sasl"
+	"github.com/emersion/go-smtp"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+// Dispatcher delivers a submission to a single subscriber. Each Dispatch call
+// is a single attempt with no retry of its own; a non-nil error means this
+// attempt failed. Webhook retries with backoff are done by
+// notifyHub.deliverWithRetry.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, sub Subscription, submission db.Row) error
+}
+
+// backoffSchedule is the delay before each retry attempt of a webhook delivery.
+// After the final entry is exhausted, the delivery is moved to the dead letter table.
+var backoffSchedule = []time.Duration{
+	1 * time.Second,
+	4 * time.Second,
+	16 * time.Second,
+	60 * time.Second,
+	300 * time.Second,
+}
+
+// webhookDispatcher POSTs the raw submission JSON to sub.Target, signing the
+// body with the subscription's secret.
+type webhookDispatcher struct {
+	client *http.Client
+}
+
+func newWebhookDispatcher() *webhookDispatcher {
+	return &webhookDispatcher{client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// signPayload returns the hex-encoded HMAC-SHA256 of body using secret, in the
+// "sha256=<hex>" form used for the X-Signature header.
+func signPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *webhookDispatcher) Dispatch(ctx context.Context, sub Subscription, submission db.Row) error {
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return fmt.Errorf("marshal submission: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature", signPayload(sub.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// emailTemplate renders the HTML body for a submission notification email.
+var emailTemplate = template.Must(template.New("submission").Parse(`
+<h2>New submission</h2>
+<p><strong>Name:</strong> {{.Name}}</p>
+<p><strong>Email:</strong> {{.Email}}</p>
+<p><strong>Message:</strong> {{.Message}}</p>
+<p><small>Submission #{{.ID}} at {{.Timestamp}}</small></p>
+`))
+
+// smtpDispatcher sends the submission as an HTML email via SMTP.
+type smtpDispatcher struct {
+	addr string
+	from string
+	auth sasl.Client
+}
+
+func newSMTPDispatcher(addr, from string, auth sasl.Client) *smtpDispatcher {
+	return &smtpDispatcher{addr: addr, from: from, auth: auth}
+}
+
+func (d *smtpDispatcher) Dispatch(ctx context.Context, sub Subscription, submission db.Row) error {
+	var body bytes.Buffer
+	if err := emailTemplate.Execute(&body, submission); err != nil {
+		return fmt.Errorf("render email: %w", err)
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: New submission #%d\r\nContent-Type: text/html; charset=UTF-8\r\n\r\n%s",
+		d.from, sub.Target, submission.ID, body.String(),
+	)
+
+	return smtp.SendMail(d.addr, d.auth, d.from, []string{sub.Target}, strings.NewReader(msg))
+}
+
+// fcmMessagingScope is the OAuth2 scope requested for the service account
+// token used to call the FCM HTTP v1 API.
+const fcmMessagingScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// fcmDispatcher sends a mobile push notification via the Firebase Cloud
+// Messaging HTTP v1 API. It authenticates directly against a service account
+// key with a hand-rolled JWT bearer exchange instead of depending on
+// firebase.google.com/go, whose root package drags in the Firestore and
+// Cloud Storage client libraries for a service that only ever sends FCM push.
+type fcmDispatcher struct {
+	client      *http.Client
+	projectID   string
+	clientEmail string
+	privateKey  *rsa.PrivateKey
+	tokenURI    string
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+}
+
+// serviceAccountKey is the subset of fields used from a Google service
+// account JSON key file, as downloaded from the Firebase console.
+type serviceAccountKey struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// newFCMDispatcher loads a service account key from credsFile and returns a
+// dispatcher that mints its own short-lived OAuth2 access tokens for it.
+func newFCMDispatcher(credsFile string) (*fcmDispatcher, error) {
+	raw, err := os.ReadFile(credsFile)
+	if err != nil {
+		return nil, fmt.Errorf("read FCM credentials: %w", err)
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(raw, &key); err != nil {
+		return nil, fmt.Errorf("parse FCM credentials: %w", err)
+	}
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return nil, fmt.Errorf("FCM credentials: no PEM block in private_key")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse FCM private key: %w", err)
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("FCM credentials: private key is not RSA")
+	}
+	tokenURI := key.TokenURI
+	if tokenURI == "" {
+		tokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &fcmDispatcher{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		projectID:   key.ProjectID,
+		clientEmail: key.ClientEmail,
+		privateKey:  rsaKey,
+		tokenURI:    tokenURI,
+	}, nil
+}
+
+// accessTokenFor returns a cached bearer token for the FCM API, minting a new
+// one via the service account's JWT bearer grant once the cached token is
+// missing or within a minute of expiring.
+func (d *fcmDispatcher) accessTokenFor(ctx context.Context) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.accessToken != "" && time.Until(d.expiresAt) > time.Minute {
+		return d.accessToken, nil
+	}
+
+	now := time.Now()
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   d.clientEmail,
+		"scope": fcmMessagingScope,
+		"aud":   d.tokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("build JWT claims: %w", err)
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, d.privateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("sign JWT: %w", err)
+	}
+	jwt := unsigned + "." + base64.RawURLEncoding.EncodeToString(sig)
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {jwt},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.tokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("build token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("decode token response: %w", err)
+	}
+
+	d.accessToken = tok.AccessToken
+	d.expiresAt = now.Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return d.accessToken, nil
+}
+
+func (d *fcmDispatcher) Dispatch(ctx context.Context, sub Subscription, submission db.Row) error {
+	token, err := d.accessTokenFor(ctx)
+	if err != nil {
+		return fmt.Errorf("fcm auth: %w", err)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"token": sub.Target,
+			"notification": map[string]string{
+				"title": "New submission",
+				"body":  fmt.Sprintf("%s: %s", submission.Name, submission.Message),
+			},
+			"data": map[string]string{
+				"submissionId": fmt.Sprintf("%d", submission.ID),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal FCM message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://fcm.googleapis.com/v1/projects/%s/messages:send", d.projectID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build FCM request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("FCM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("FCM returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// dispatcherFor resolves the Dispatcher implementation for a subscription kind.
+// It returns nil for an unknown kind or for a dispatcher that is not configured.
+func dispatcherFor(kind string, webhook *webhookDispatcher, email *smtpDispatcher, fcm *fcmDispatcher) Dispatcher {
+	switch kind {
+	case subscriptionKindWebhook:
+		if webhook == nil {
+			return nil
+		}
+		return webhook
+	case subscriptionKindEmail:
+		if email == nil {
+			return nil
+		}
+		return email
+	case subscriptionKindFCM:
+		if fcm == nil {
+			return nil
+		}
+		return fcm
+	default:
+		return nil
+	}
+}
+
+// matchesFilter evaluates a subscription's filter expression against a
+// submission. The expression is a semicolon-separated list of
+// "field=regexp" clauses (field one of name/email/message); a submission
+// matches only if every clause matches. An empty expression matches everything.
+func matchesFilter(filterExpr string, submission db.Row) (bool, error) {
+	if strings.TrimSpace(filterExpr) == "" {
+		return true, nil
+	}
+	for _, clause := range strings.Split(filterExpr, ";") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		parts := strings.SplitN(clause, "=", 2)
+		if len(parts) != 2 {
+			return false, fmt.Errorf("invalid filter clause: %q", clause)
+		}
+		field, pattern := parts[0], parts[1]
+		var value string
+		switch field {
+		case "name":
+			value = submission.Name
+		case "email":
+			value = submission.Email
+		case "message":
+			value = submission.Message
+		default:
+			return false, fmt.Errorf("unknown filter field: %q", field)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, fmt.Errorf("invalid regexp for field %q: %w", field, err)
+		}
+		if !re.MatchString(value) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
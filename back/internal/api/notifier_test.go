@@ -0,0 +1,71 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+func TestSignPayloadIsDeterministicAndSecretDependent(t *testing.T) {
+	body := []byte(`{"a":1}`)
+
+	sig1 := signPayload("secret-a", body)
+	sig2 := signPayload("secret-a", body)
+	if sig1 != sig2 {
+		t.Fatalf("expected signPayload to be deterministic for the same secret and body: %q != %q", sig1, sig2)
+	}
+
+	sig3 := signPayload("secret-b", body)
+	if sig1 == sig3 {
+		t.Fatal("expected a different secret to produce a different signature")
+	}
+
+	const prefix = "sha256="
+	if len(sig1) <= len(prefix) || sig1[:len(prefix)] != prefix {
+		t.Fatalf("expected signature to be prefixed %q, got %q", prefix, sig1)
+	}
+}
+
+func TestMatchesFilterEmptyExpressionMatchesEverything(t *testing.T) {
+	ok, err := matchesFilter("", db.Row{Name: "anyone"})
+	if err != nil {
+		t.Fatalf("matchesFilter: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected empty filter expression to match")
+	}
+}
+
+func TestMatchesFilterAllClausesMustMatch(t *testing.T) {
+	submission := db.Row{Name: "Ada Lovelace", Email: "ada@example.com", Message: "hello world"}
+
+	ok, err := matchesFilter("name=^Ada;email=example\\.com$", submission)
+	if err != nil {
+		t.Fatalf("matchesFilter: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected submission matching both clauses to match")
+	}
+
+	ok, err = matchesFilter("name=^Ada;message=^goodbye", submission)
+	if err != nil {
+		t.Fatalf("matchesFilter: %v", err)
+	}
+	if ok {
+		t.Fatal("expected submission failing one of two clauses to not match")
+	}
+}
+
+func TestMatchesFilterRejectsUnknownFieldAndBadClause(t *testing.T) {
+	submission := db.Row{Name: "Ada"}
+
+	if _, err := matchesFilter("nope=anything", submission); err == nil {
+		t.Fatal("expected an error for an unknown filter field")
+	}
+	if _, err := matchesFilter("name-missing-equals", submission); err == nil {
+		t.Fatal("expected an error for a clause without '='")
+	}
+	if _, err := matchesFilter("name=[", submission); err == nil {
+		t.Fatal("expected an error for an invalid regexp")
+	}
+}
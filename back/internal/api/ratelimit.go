@@ -0,0 +1,155 @@
+package api
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenBucket is a minimal token-bucket rate limiter: it refills at `rate`
+// tokens/sec up to `burst` and is safe for concurrent use.
+type tokenBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	rate     float64
+	burst    float64
+	lastFill time.Time
+}
+
+func newTokenBucket(ratePerSec float64, burst int) *tokenBucket {
+	return &tokenBucket{tokens: float64(burst), rate: ratePerSec, burst: float64(burst), lastFill: time.Now()}
+}
+
+// allow reports whether a single token is available and consumes it if so.
+func (b *tokenBucket) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	elapsed := now.Sub(b.lastFill).Seconds()
+	b.lastFill = now
+	b.tokens += elapsed * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// visitor tracks per-key (token or IP) rate limiting state.
+type visitor struct {
+	submitBucket *tokenBucket
+	sseConns     int
+	lastSeen     time.Time
+	mu           sync.Mutex
+}
+
+// visitorStore holds one visitor per rate-limit key, swept periodically to
+// bound memory for anonymous (IP-keyed) visitors that never come back.
+type visitorStore struct {
+	mu       sync.Mutex
+	visitors map[string]*visitor
+
+	submitRatePerMin float64
+	submitBurst      int
+	maxSSEPerVisitor int
+}
+
+func newVisitorStore(submitRatePerMin float64, submitBurst, maxSSEPerVisitor int) *visitorStore {
+	return &visitorStore{
+		visitors:         make(map[string]*visitor),
+		submitRatePerMin: submitRatePerMin,
+		submitBurst:      submitBurst,
+		maxSSEPerVisitor: maxSSEPerVisitor,
+	}
+}
+
+func (s *visitorStore) get(key string) *visitor {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.visitors[key]
+	if !ok {
+		v = &visitor{submitBucket: newTokenBucket(s.submitRatePerMin/60, s.submitBurst)}
+		s.visitors[key] = v
+	}
+	v.lastSeen = time.Now()
+	return v
+}
+
+// sweep drops visitors idle for longer than maxIdle. Run on a ticker from startSweeper.
+func (s *visitorStore) sweep(maxIdle time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cutoff := time.Now().Add(-maxIdle)
+	for key, v := range s.visitors {
+		v.mu.Lock()
+		idle := v.lastSeen.Before(cutoff) && v.sseConns == 0
+		v.mu.Unlock()
+		if idle {
+			delete(s.visitors, key)
+		}
+	}
+}
+
+// startSweeper runs sweep on an interval until the process exits.
+func (s *visitorStore) startSweeper(interval, maxIdle time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.sweep(maxIdle)
+		}
+	}()
+}
+
+// visitorKey identifies the caller for rate-limiting purposes: the auth token
+// if requireScope resolved one, otherwise the client IP.
+func visitorKey(c *gin.Context) string {
+	if tok, ok := authContext(c); ok {
+		return "token:" + strconv.FormatInt(tok.ID, 10)
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// submitRateLimit enforces the token-bucket limit on submission-style endpoints.
+func submitRateLimit(store *visitorStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v := store.get(visitorKey(c))
+		if !v.submitBucket.allow() {
+			c.Writer.Header().Set("Retry-After", "60")
+			c.AbortWithStatusJSON(429, gin.H{"error": true, "message": "rate limit exceeded", "code": 429, "retryAfterSeconds": 60})
+			return
+		}
+		c.Next()
+	}
+}
+
+// sseConnLimit caps concurrent streaming connections per visitor and releases
+// the slot once the handler returns (the client disconnects or the stream ends).
+func sseConnLimit(store *visitorStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		v := store.get(visitorKey(c))
+
+		v.mu.Lock()
+		if v.sseConns >= store.maxSSEPerVisitor {
+			v.mu.Unlock()
+			c.Writer.Header().Set("Retry-After", "30")
+			c.AbortWithStatusJSON(429, gin.H{"error": true, "message": "too many concurrent streams", "code": 429, "retryAfterSeconds": 30})
+			return
+		}
+		v.sseConns++
+		v.mu.Unlock()
+
+		defer func() {
+			v.mu.Lock()
+			v.sseConns--
+			v.mu.Unlock()
+		}()
+
+		c.Next()
+	}
+}
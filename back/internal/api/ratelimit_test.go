@@ -0,0 +1,49 @@
+package api
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucketAllowsUpToBurstThenBlocks(t *testing.T) {
+	b := newTokenBucket(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if !b.allow() {
+			t.Fatalf("expected token %d of burst to be allowed", i)
+		}
+	}
+	if b.allow() {
+		t.Fatal("expected bucket to be empty after burst is exhausted")
+	}
+}
+
+func TestTokenBucketRefillsOverTime(t *testing.T) {
+	b := newTokenBucket(10, 1)
+	if !b.allow() {
+		t.Fatal("expected initial token to be allowed")
+	}
+	if b.allow() {
+		t.Fatal("expected bucket to be empty immediately after consuming its only token")
+	}
+
+	b.lastFill = time.Now().Add(-200 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("expected a token to have refilled after 200ms at 10/sec")
+	}
+}
+
+func TestTokenBucketNeverExceedsBurst(t *testing.T) {
+	b := newTokenBucket(100, 2)
+	b.lastFill = time.Now().Add(-time.Hour)
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		if b.allow() {
+			allowed++
+		}
+	}
+	if allowed != 2 {
+		t.Fatalf("expected refill to be capped at burst=2, got %d allowed calls", allowed)
+	}
+}
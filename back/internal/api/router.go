@@ -0,0 +1,97 @@
+package api
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/config"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+// corsMiddleware allows configured origins, mirroring the per-origin
+// allow/deny logic the streaming handlers use for their own CORS headers.
+func corsMiddleware(allowedOrigins map[string]bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		norm := config.NormalizeOrigin(origin)
+		if allowedOrigins["*"] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if allowedOrigins[norm] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Vary", "Origin")
+		}
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusOK)
+			return
+		}
+		c.Next()
+	}
+}
+
+// NewRouter builds and wires the full Gin engine: CORS, auth/rate-limit
+// middleware, and every route the service exposes.
+func NewRouter(cfg config.Options, conn *sql.DB, store *db.SubmissionStore, hub *broadcaster.Hub) *gin.Engine {
+	if err := bootstrapAdminToken(conn, cfg.AdminBootstrapToken); err != nil {
+		log.Println("failed to bootstrap admin token:", err)
+	}
+
+	visitors := newVisitorStore(cfg.SubmitRatePerMin, cfg.SubmitBurst, cfg.MaxSSEPerVisitor)
+	visitors.startSweeper(5*time.Minute, 30*time.Minute)
+
+	notifier := buildNotifyHub(conn, cfg.NotifierWorkers, cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword, cfg.FCMCredsFile)
+	wsUpgrader := newWSUpgrader(cfg.AllowedOrigins)
+
+	r := gin.Default()
+	r.RemoveExtraSlash = true
+	r.Use(corsMiddleware(cfg.AllowedOrigins))
+
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "Instant Notification service is running")
+	})
+
+	// Admin-only management of bearer tokens used by the auth layer below.
+	r.POST("/api/auth/tokens", requireScope(conn, scopeAdmin), createTokenHandler(conn))
+	r.GET("/api/auth/tokens", requireScope(conn, scopeAdmin), listTokensHandler(conn))
+	r.DELETE("/api/auth/tokens/:id", requireScope(conn, scopeAdmin), deleteTokenHandler(conn))
+
+	startIdempotencySweeper(conn, time.Hour)
+
+	r.POST("/api/submit-form", requireScope(conn, scopePublish), submitRateLimit(visitors), idempotent(conn), submitFormHandler(store, hub, notifier))
+	r.GET("/api/submit-form", listSubmissionsHandler(store, 10))
+	r.GET("/api/leads", listSubmissionsHandler(store, 20))
+	r.POST("/api/submissions/:id/latency", idempotent(conn), submissionLatencyHandler(store))
+
+	// SSE stream of new submissions, backed by the "submissions" topic on the shared hub.
+	r.GET("/api/stream/submissions", requireScope(conn, scopeSubscribe), sseConnLimit(visitors), legacySubmissionsStreamHandler(hub, cfg.AllowedOrigins))
+
+	// WebSocket equivalent of the SSE stream above, supporting a
+	// {"type":"subscribe","topics":[...]} frame to listen on additional topics.
+	r.GET("/api/ws/submissions", requireScope(conn, scopeSubscribe), sseConnLimit(visitors), wsSubmissionsHandler(hub, wsUpgrader))
+
+	r.GET("/api/stats", statsHandler(hub))
+
+	// Manage notification targets (webhook/email/fcm) and inspect their delivery history.
+	r.POST("/api/subscriptions", requireScope(conn, scopeAdmin), createSubscriptionHandler(conn))
+	r.GET("/api/subscriptions/:id/deliveries", requireScope(conn, scopeAdmin), listDeliveriesHandler(conn))
+
+	// Generic multi-topic pub/sub with history replay, layered alongside the
+	// legacy single-firehose submissions stream above. Publishing requires the
+	// publish scope and streaming the subscribe scope; scopes are not tied to a
+	// topic, so any token with the scope can use every topic.
+	r.POST("/api/topics/:topic/publish", requireScope(conn, scopePublish), submitRateLimit(visitors), topicPublishHandler(hub))
+	r.GET("/api/topics/:topic/stream", requireScope(conn, scopeSubscribe), sseConnLimit(visitors), topicStreamHandler(hub, cfg.AllowedOrigins))
+
+	// API contract, generated via `make swagger` from the handlers' @-annotations.
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+
+	return r
+}
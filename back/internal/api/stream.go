@@ -0,0 +1,378 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/config"
+)
+
+// topicPublishHandler handles POST /api/topics/:topic/publish. The body is stored
+// verbatim and replayed as-is to subscribers joining later via ?since=.
+//
+// @Summary      Publish a message to a topic
+// @Tags         topics
+// @Accept       json
+// @Produce      json
+// @Param        topic  path  string  true  "Topic name"
+// @Param        message  body  object  true  "Arbitrary JSON payload, stored and replayed verbatim"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      429  {object}  map[string]interface{}
+// @Router       /api/topics/{topic}/publish [post]
+func topicPublishHandler(hub *broadcaster.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			name := c.Param("topic")
+			if name == "" {
+				return nil, errBadRequest("missing topic")
+			}
+
+			body, err := c.GetRawData()
+			if err != nil {
+				return nil, errBadRequest("failed to read body")
+			}
+			if len(body) == 0 {
+				body = []byte("null")
+			}
+			if !json.Valid(body) {
+				return nil, errBadRequest("body must be valid JSON")
+			}
+
+			msg := hub.Publish(name, json.RawMessage(body))
+			return gin.H{"id": msg.ID, "topic": msg.Topic, "createdAt": msg.CreatedAt}, nil
+		})
+	}
+}
+
+// topicStreamHandler handles GET /api/topics/:topic/stream. It replays any history
+// the ?since= cursor requires, then switches to live streaming. Output format is
+// SSE by default, or chunked JSON lines when negotiated via ?format=json or an
+// `Accept: application/x-ndjson` header.
+//
+// @Summary      Stream a topic's messages
+// @Description  Streams as text/event-stream by default, or newline-delimited JSON with ?format=json.
+// @Tags         topics
+// @Produce      text/event-stream
+// @Produce      application/x-ndjson
+// @Param        topic   path   string  true   "Topic name"
+// @Param        since   query  string  false  "Replay cursor: a message ID, a unix millisecond timestamp, or \"all\""
+// @Param        format  query  string  false  "json to receive newline-delimited JSON instead of SSE"
+// @Success      200  {object}  broadcaster.Message
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      429  {object}  map[string]interface{}
+// @Router       /api/topics/{topic}/stream [get]
+func topicStreamHandler(hub *broadcaster.Hub, allowedOrigins map[string]bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("topic")
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": true, "message": "missing topic", "code": 400})
+			return
+		}
+		t := hub.Topic(name)
+
+		replay, live, err := broadcaster.ResolveSince(t, c.Query("since"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": true, "message": err.Error(), "code": 400})
+			return
+		}
+
+		useJSON := c.Query("format") == "json" || c.GetHeader("Accept") == "application/x-ndjson"
+
+		if allowedOrigins["*"] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		} else if origin := c.GetHeader("Origin"); allowedOrigins[config.NormalizeOrigin(origin)] {
+			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+			c.Writer.Header().Set("Vary", "Origin")
+		}
+
+		flusher, ok := c.Writer.(http.Flusher)
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+
+		var sub *broadcaster.Subscriber
+		if live {
+			sub = t.Subscribe(broadcaster.SubscriberSSE)
+			defer t.Unsubscribe(sub)
+		}
+
+		if useJSON {
+			c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+			c.Writer.Header().Set("Cache-Control", "no-cache")
+			writeJSONLine := func(v interface{}) {
+				line, _ := json.Marshal(v)
+				c.Writer.Write(append(line, '\n'))
+				flusher.Flush()
+			}
+			for _, m := range replay {
+				writeJSONLine(m)
+			}
+			if !live {
+				return
+			}
+			notify := c.Request.Context().Done()
+			for {
+				select {
+				case <-notify:
+					return
+				case ev := <-sub.Control:
+					if ev.Kind == broadcaster.ControlClose {
+						writeJSONLine(gin.H{"type": "close", "reason": "lagging"})
+						return
+					}
+					writeJSONLine(gin.H{"type": "lagged", "missed": ev.Missed})
+				case m, ok := <-sub.Queue:
+					if !ok {
+						return
+					}
+					writeJSONLine(m)
+				}
+			}
+		}
+
+		c.Writer.Header().Set("Content-Type", "text/event-stream")
+		c.Writer.Header().Set("Cache-Control", "no-cache")
+		c.Writer.Header().Set("Connection", "keep-alive")
+		fmt.Fprintf(c.Writer, ": connected\n\n")
+		flusher.Flush()
+
+		writeSSE := func(m broadcaster.Message) {
+			payload, _ := json.Marshal(m)
+			fmt.Fprintf(c.Writer, "id: %d\n", m.ID)
+			fmt.Fprintf(c.Writer, "event: message\n")
+			fmt.Fprintf(c.Writer, "data: %s\n\n", payload)
+			flusher.Flush()
+		}
+		for _, m := range replay {
+			writeSSE(m)
+		}
+		if !live {
+			return
+		}
+
+		heartbeat := time.NewTicker(30 * time.Second)
+		defer heartbeat.Stop()
+		notify := c.Request.Context().Done()
+		for {
+			select {
+			case <-notify:
+				return
+			case <-heartbeat.C:
+				fmt.Fprintf(c.Writer, ": ping %d\n\n", time.Now().Unix())
+				flusher.Flush()
+			case ev := <-sub.Control:
+				if ev.Kind == broadcaster.ControlClose {
+					fmt.Fprintf(c.Writer, "event: close\n")
+					fmt.Fprintf(c.Writer, "data: {\"reason\":\"lagging\"}\n\n")
+					flusher.Flush()
+					return
+				}
+				fmt.Fprintf(c.Writer, "event: lagged\n")
+				fmt.Fprintf(c.Writer, "data: {\"missed\":%d}\n\n", ev.Missed)
+				flusher.Flush()
+			case m, ok := <-sub.Queue:
+				if !ok {
+					return
+				}
+				writeSSE(m)
+			}
+		}
+	}
+}
+
+// legacySubmissionsStreamHandler adapts topicStreamHandler to the fixed
+// "submissions" topic for the original /api/stream/submissions endpoint.
+func legacySubmissionsStreamHandler(hub *broadcaster.Hub, allowedOrigins map[string]bool) gin.HandlerFunc {
+	inner := topicStreamHandler(hub, allowedOrigins)
+	return func(c *gin.Context) {
+		c.Params = append(c.Params, gin.Param{Key: "topic", Value: broadcaster.LegacySubmissionsTopic})
+		inner(c)
+	}
+}
+
+// statsHandler handles GET /api/stats.
+//
+// @Summary      Hub-wide subscriber and throughput stats
+// @Tags         topics
+// @Produce      json
+// @Success      200  {object}  broadcaster.StatsReport
+// @Router       /api/stats [get]
+func statsHandler(hub *broadcaster.Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, hub.StatsSnapshot())
+	}
+}
+
+// newWSUpgrader upgrades GET /api/ws/submissions connections. Origin checking
+// is handled by the same allow-list as the rest of the API rather than
+// gorilla's default (same-origin-only) check.
+func newWSUpgrader(allowedOrigins map[string]bool) websocket.Upgrader {
+	return websocket.Upgrader{
+		ReadBufferSize:  4096,
+		WriteBufferSize: 4096,
+		CheckOrigin: func(r *http.Request) bool {
+			if allowedOrigins["*"] {
+				return true
+			}
+			return allowedOrigins[config.NormalizeOrigin(r.Header.Get("Origin"))]
+		},
+	}
+}
+
+// wsClientFrame is a message sent by the client, e.g. to add topic filters.
+type wsClientFrame struct {
+	Type   string   `json:"type"`
+	Topics []string `json:"topics"`
+}
+
+// wsServerFrame is a message sent to the client: a delivered message, a lag
+// warning, or a forced close notice.
+type wsServerFrame struct {
+	Type   string          `json:"type"`
+	Topic  string          `json:"topic,omitempty"`
+	ID     int64           `json:"id,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Missed int             `json:"missed,omitempty"`
+}
+
+const (
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+)
+
+// wsSubmissionsHandler handles GET /api/ws/submissions. It emits the same
+// submission events SSE clients get (on the "submissions" topic) and accepts a
+// {"type":"subscribe","topics":[...]} frame to additionally listen on other
+// topics. Each subscribed topic gets its own bounded subscriber; a subscriber
+// that lags past the disconnect threshold closes the socket with code 1008.
+func wsSubmissionsHandler(hub *broadcaster.Hub, upgrader websocket.Upgrader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		out := make(chan wsServerFrame, 64)
+		done := make(chan struct{})
+		var closeOnce sync.Once
+		stop := func() { closeOnce.Do(func() { close(done) }) }
+
+		var subsMu sync.Mutex
+		subs := map[string]*broadcaster.Subscriber{}
+		addTopic := func(name string) {
+			subsMu.Lock()
+			defer subsMu.Unlock()
+			if _, ok := subs[name]; ok {
+				return
+			}
+			sub := hub.Topic(name).Subscribe(broadcaster.SubscriberWS)
+			subs[name] = sub
+			go pumpSubscriber(sub, out, done)
+		}
+		addTopic(broadcaster.LegacySubmissionsTopic)
+
+		defer func() {
+			stop()
+			subsMu.Lock()
+			defer subsMu.Unlock()
+			for name, sub := range subs {
+				hub.Topic(name).Unsubscribe(sub)
+			}
+		}()
+
+		conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		conn.SetPongHandler(func(string) error {
+			conn.SetReadDeadline(time.Now().Add(wsPongWait))
+			return nil
+		})
+
+		// Reader goroutine: client pings/pongs are handled by gorilla internally;
+		// we only need to read application frames (subscribe requests).
+		go func() {
+			for {
+				var frame wsClientFrame
+				if err := conn.ReadJSON(&frame); err != nil {
+					stop()
+					return
+				}
+				if frame.Type == "subscribe" {
+					for _, t := range frame.Topics {
+						addTopic(t)
+					}
+				}
+			}
+		}()
+
+		ping := time.NewTicker(wsPingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ping.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+					return
+				}
+			case frame, ok := <-out:
+				if !ok {
+					return
+				}
+				if err := conn.WriteJSON(frame); err != nil {
+					return
+				}
+				if frame.Type == "close" {
+					_ = conn.WriteControl(
+						websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "lagging"),
+						time.Now().Add(5*time.Second),
+					)
+					return
+				}
+			}
+		}
+	}
+}
+
+// pumpSubscriber forwards a single topic subscriber's messages and control
+// events onto the connection's shared output channel until done fires.
+func pumpSubscriber(sub *broadcaster.Subscriber, out chan<- wsServerFrame, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case ev := <-sub.Control:
+			if ev.Kind == broadcaster.ControlClose {
+				select {
+				case out <- wsServerFrame{Type: "close"}:
+				case <-done:
+				}
+				return
+			}
+			select {
+			case out <- wsServerFrame{Type: "lagged", Topic: sub.Topic, Missed: ev.Missed}:
+			case <-done:
+				return
+			}
+		case m, ok := <-sub.Queue:
+			if !ok {
+				return
+			}
+			select {
+			case out <- wsServerFrame{Type: "message", Topic: m.Topic, ID: m.ID, Data: m.Data}:
+			case <-done:
+				return
+			}
+		}
+	}
+}
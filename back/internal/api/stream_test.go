@@ -0,0 +1,107 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+)
+
+// TestPumpSubscriberEmitsCloseFrameOnControlClose guards the lag->close path:
+// once a subscriber's consumer posts a ControlClose event (because it lagged
+// past the disconnect threshold), pumpSubscriber must forward a "close" frame
+// and return instead of continuing to forward further queue messages.
+func TestPumpSubscriberEmitsCloseFrameOnControlClose(t *testing.T) {
+	sub := &broadcaster.Subscriber{
+		Kind:    broadcaster.SubscriberWS,
+		Topic:   broadcaster.LegacySubmissionsTopic,
+		Queue:   make(chan broadcaster.Message, 1),
+		Control: make(chan broadcaster.ControlEvent, 1),
+	}
+	sub.Control <- broadcaster.ControlEvent{Kind: broadcaster.ControlClose}
+
+	out := make(chan wsServerFrame, 1)
+	done := make(chan struct{})
+	defer close(done)
+
+	pumpDone := make(chan struct{})
+	go func() {
+		pumpSubscriber(sub, out, done)
+		close(pumpDone)
+	}()
+
+	select {
+	case frame := <-out:
+		if frame.Type != "close" {
+			t.Fatalf("expected a close frame, got %+v", frame)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for pumpSubscriber to forward the close control event")
+	}
+
+	select {
+	case <-pumpDone:
+	case <-time.After(time.Second):
+		t.Fatal("expected pumpSubscriber to return after emitting the close frame")
+	}
+}
+
+// TestWSSubmissionsHandlerConcurrentDisconnect is a regression test for the
+// double-close/data race fixed previously: many clients connect and disconnect
+// abruptly while messages are published concurrently, racing the reader
+// goroutine's stop() against the handler's deferred cleanup. Run with -race.
+func TestWSSubmissionsHandlerConcurrentDisconnect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hub := broadcaster.NewHub(64, 0)
+	upgrader := newWSUpgrader(map[string]bool{"*": true})
+
+	r := gin.New()
+	r.GET("/ws", wsSubmissionsHandler(hub, upgrader))
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+
+	stopPublish := make(chan struct{})
+	var publishWG sync.WaitGroup
+	publishWG.Add(1)
+	go func() {
+		defer publishWG.Done()
+		for {
+			select {
+			case <-stopPublish:
+				return
+			default:
+				hub.Publish(broadcaster.LegacySubmissionsTopic, json.RawMessage(`{"x":1}`))
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+			if err != nil {
+				t.Errorf("dial: %v", err)
+				return
+			}
+			defer conn.Close()
+			_ = conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			_, _, _ = conn.ReadMessage()
+		}()
+	}
+	wg.Wait()
+
+	close(stopPublish)
+	publishWG.Wait()
+}
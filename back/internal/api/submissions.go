@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+// submissionPayload is the broadcast shape for a freshly accepted submission,
+// published to the legacy "submissions" topic for SSE/WS/JSON subscribers.
+type submissionPayload struct {
+	ID              int64  `json:"id"`
+	Name            string `json:"name"`
+	Email           string `json:"email"`
+	Message         string `json:"message"`
+	ClientSubmitAt  int64  `json:"clientSubmitAt,omitempty"`
+	ServerBroadcast int64  `json:"serverBroadcastAt"`
+}
+
+// parseLimit reads a bounded "?limit=" query param, defaulting to def. A
+// value that is not an integer from 1 to 200 is ignored and def is used.
+func parseLimit(c *gin.Context, def int) int {
+	limit := def
+	if l := c.Query("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 && v <= 200 {
+			limit = v
+		}
+	}
+	return limit
+}
+
+// submitFormHandler handles POST /api/submit-form: it saves the submission,
+// publishes it to the "submissions" topic, and enqueues it for notification fan-out.
+//
+// @Summary      Submit a form
+// @Description  Saves a new submission, broadcasts it to subscribers, and fans it out to notification subscriptions. Supports an Idempotency-Key header to safely retry.
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key  header  string      false  "Client-generated key to make retries safe"
+// @Param        submission       body    db.Submission  true   "Submission payload"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}  "Idempotency-Key reused with a different body"
+// @Failure      429  {object}  map[string]interface{}
+// @Router       /api/submit-form [post]
+func submitFormHandler(store *db.SubmissionStore, hub *broadcaster.Hub, notifier *notifyHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			var sub db.Submission
+			if err := c.ShouldBindJSON(&sub); err != nil {
+				return nil, errBadRequest("invalid input")
+			}
+
+			id, err := store.Insert(sub)
+			if err != nil {
+				return nil, errInternal("failed to save submission")
+			}
+
+			serverBroadcastAt := time.Now().UnixMilli()
+			_ = store.SetServerBroadcastAt(id, serverBroadcastAt)
+
+			payload, _ := json.Marshal(submissionPayload{
+				ID:              id,
+				Name:            sub.Name,
+				Email:           sub.Email,
+				Message:         sub.Message,
+				ClientSubmitAt:  sub.ClientSubmitAt,
+				ServerBroadcast: serverBroadcastAt,
+			})
+			hub.Publish(broadcaster.LegacySubmissionsTopic, payload)
+
+			if row, err := store.Get(id); err == nil {
+				notifier.enqueue(row)
+			}
+
+			return gin.H{"message": "Submission saved", "id": id}, nil
+		})
+	}
+}
+
+// listSubmissionsHandler handles GET /api/submit-form and GET /api/leads,
+// which both return the most recent submissions (proof/debug endpoints that
+// predate the topic stream).
+//
+// @Summary      List recent submissions
+// @Tags         submissions
+// @Produce      json
+// @Param        limit  query  int  false  "Max rows to return, 1-200 (default varies by route; other values use the default)"
+// @Success      200  {array}  db.Row
+// @Failure      500  {object}  map[string]interface{}
+// @Router       /api/submit-form [get]
+func listSubmissionsHandler(store *db.SubmissionStore, defaultLimit int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			rows, err := store.List(parseLimit(c, defaultLimit))
+			if err != nil {
+				return nil, errInternal("failed to query submissions")
+			}
+			return rows, nil
+		})
+	}
+}
+
+// submissionLatencyHandler handles POST /api/submissions/:id/latency, storing
+// whichever client-reported latency fields the request included.
+//
+// @Summary      Report client-side latency for a submission
+// @Tags         submissions
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key  header  string  false  "Client-generated key to make retries safe"
+// @Param        id               path    int     true   "Submission ID"
+// @Param        latency          body    db.LatencyUpdate  true  "Latency fields to update"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      409  {object}  map[string]interface{}  "Idempotency-Key reused with a different body"
+// @Router       /api/submissions/{id}/latency [post]
+func submissionLatencyHandler(store *db.SubmissionStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil || id <= 0 {
+				return nil, errBadRequest("invalid id")
+			}
+
+			var body struct {
+				SubmitToServerMs  *int64 `json:"submitToServerMs"`
+				ServerToDisplayMs *int64 `json:"serverToDisplayMs"`
+				SubmitToDisplayMs *int64 `json:"submitToDisplayMs"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				return nil, errBadRequest("invalid payload")
+			}
+
+			ok, err := store.UpdateLatency(id, db.LatencyUpdate{
+				SubmitToServerMs:  body.SubmitToServerMs,
+				ServerToDisplayMs: body.ServerToDisplayMs,
+				SubmitToDisplayMs: body.SubmitToDisplayMs,
+			})
+			if err != nil {
+				return nil, errInternal("failed to update latency")
+			}
+			if !ok {
+				return nil, errBadRequest("no fields to update")
+			}
+			return gin.H{"ok": true}, nil
+		})
+	}
+}
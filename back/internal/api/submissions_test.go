@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/broadcaster"
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+func newTestStore(t *testing.T) *db.SubmissionStore {
+	t.Helper()
+	conn, err := db.Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return db.NewSubmissionStore(conn)
+}
+
+func TestSubmitFormHandlerSavesAndBroadcasts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestStore(t)
+	hub := broadcaster.NewHub(200, 0)
+	topic := hub.Topic(broadcaster.LegacySubmissionsTopic)
+	sub := topic.Subscribe(broadcaster.SubscriberSSE)
+	defer topic.Unsubscribe(sub)
+
+	notifier := newNotifyHub(nil, 0, newWebhookDispatcher(), nil, nil)
+
+	r := gin.New()
+	r.POST("/api/submit-form", submitFormHandler(store, hub, notifier))
+
+	body := `{"name":"Ada","email":"ada@example.com","message":"hi"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/submit-form", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		ID int64 `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal response: %v", err)
+	}
+	if resp.ID == 0 {
+		t.Fatalf("expected a non-zero id")
+	}
+
+	select {
+	case msg := <-sub.Queue:
+		var payload submissionPayload
+		if err := json.Unmarshal(msg.Data, &payload); err != nil {
+			t.Fatalf("unmarshal broadcast payload: %v", err)
+		}
+		if payload.Name != "Ada" {
+			t.Fatalf("expected broadcast name %q, got %q", "Ada", payload.Name)
+		}
+	default:
+		t.Fatal("expected submission to be broadcast to subscribers")
+	}
+
+	rows, err := store.List(10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Name != "Ada" {
+		t.Fatalf("expected stored submission for Ada, got %+v", rows)
+	}
+}
+
+func TestSubmissionLatencyHandlerRejectsEmptyBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := newTestStore(t)
+	id, err := store.Insert(db.Submission{Name: "Grace", Email: "grace@example.com", Message: "hi"})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	r := gin.New()
+	r.POST("/api/submissions/:id/latency", submissionLatencyHandler(store))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/submissions/"+strconv.FormatInt(id, 10)+"/latency", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty latency update, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/emersion/go-sasl"
+	"github.com/gin-gonic/gin"
+
+	"github.com/K2adir/instant-notification-service-GO-React/back/internal/db"
+)
+
+// Subscription kinds accepted by POST /api/subscriptions.
+const (
+	subscriptionKindWebhook = "webhook"
+	subscriptionKindEmail   = "email"
+	subscriptionKindFCM     = "fcm"
+)
+
+// Delivery statuses recorded per attempt.
+const (
+	deliveryStatusSuccess    = "success"
+	deliveryStatusFailed     = "failed"
+	deliveryStatusDeadLetter = "dead_letter"
+)
+
+// Subscription is a registered notification target: a webhook URL, an email
+// address, or an FCM device token, optionally narrowed by a filter expression.
+type Subscription struct {
+	ID         int64     `json:"id"`
+	Kind       string    `json:"kind"`
+	Target     string    `json:"target"`
+	Secret     string    `json:"-"`
+	FilterExpr string    `json:"filterExpr,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Delivery is one dispatch attempt of a submission to a subscription.
+type Delivery struct {
+	ID             int64     `json:"id"`
+	SubscriptionID int64     `json:"subscriptionId"`
+	SubmissionID   int64     `json:"submissionId"`
+	Attempt        int       `json:"attempt"`
+	Status         string    `json:"status"`
+	Error          string    `json:"error,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// buildNotifyHub wires up the notifier subsystem from environment
+// configuration: webhook delivery is always available, SMTP and FCM dispatch
+// are enabled only when their respective env vars are set so the service
+// still starts without them.
+func buildNotifyHub(conn *sql.DB, workers int, smtpAddr, smtpFrom, smtpUsername, smtpPassword, fcmCredsFile string) *notifyHub {
+	webhook := newWebhookDispatcher()
+
+	var email *smtpDispatcher
+	if smtpAddr != "" {
+		var auth sasl.Client
+		if smtpUsername != "" {
+			auth = sasl.NewPlainClient("", smtpUsername, smtpPassword)
+		}
+		email = newSMTPDispatcher(smtpAddr, smtpFrom, auth)
+	}
+
+	var fcm *fcmDispatcher
+	if fcmCredsFile != "" {
+		var err error
+		fcm, err = newFCMDispatcher(fcmCredsFile)
+		if err != nil {
+			log.Println("notifier: failed to init FCM dispatcher, FCM dispatch disabled:", err)
+			fcm = nil
+		}
+	}
+
+	return newNotifyHub(conn, workers, webhook, email, fcm)
+}
+
+// notifyHub fans new submissions out to matching subscriptions via a fixed
+// worker pool, dispatching each delivery with the appropriate Dispatcher.
+type notifyHub struct {
+	db      *sql.DB
+	jobs    chan db.Row
+	webhook *webhookDispatcher
+	email   *smtpDispatcher
+	fcm     *fcmDispatcher
+}
+
+func newNotifyHub(conn *sql.DB, workers int, webhook *webhookDispatcher, email *smtpDispatcher, fcm *fcmDispatcher) *notifyHub {
+	h := &notifyHub{db: conn, jobs: make(chan db.Row, 256), webhook: webhook, email: email, fcm: fcm}
+	for i := 0; i < workers; i++ {
+		go h.worker()
+	}
+	return h
+}
+
+// enqueue schedules fan-out for a freshly inserted submission. Non-blocking up
+// to the job queue's buffer; callers should not depend on delivery completing
+// before returning a response to the original submitter.
+func (h *notifyHub) enqueue(submission db.Row) {
+	select {
+	case h.jobs <- submission:
+	default:
+		log.Println("notifyHub: job queue full, dropping fan-out for submission", submission.ID)
+	}
+}
+
+func (h *notifyHub) worker() {
+	for submission := range h.jobs {
+		h.process(submission)
+	}
+}
+
+func (h *notifyHub) process(submission db.Row) {
+	rows, err := h.db.Query("SELECT id, kind, target, secret, filter_expr, created_at FROM subscriptions")
+	if err != nil {
+		log.Println("notifyHub: failed to load subscriptions:", err)
+		return
+	}
+	var subs []Subscription
+	for rows.Next() {
+		var s Subscription
+		var secret, filterExpr sql.NullString
+		if err := rows.Scan(&s.ID, &s.Kind, &s.Target, &secret, &filterExpr, &s.CreatedAt); err != nil {
+			log.Println("notifyHub: failed to scan subscription:", err)
+			continue
+		}
+		s.Secret = secret.String
+		s.FilterExpr = filterExpr.String
+		subs = append(subs, s)
+	}
+	rows.Close()
+
+	for _, sub := range subs {
+		matched, err := matchesFilter(sub.FilterExpr, submission)
+		if err != nil {
+			log.Println("notifyHub: bad filter for subscription", sub.ID, err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		if sub.Kind == subscriptionKindWebhook {
+			// Webhooks get their own goroutine so one slow/retrying target can't
+			// hold up delivery to every other subscriber of this submission.
+			go h.deliverWithRetry(sub, submission)
+			continue
+		}
+		h.deliverOnce(sub, submission)
+	}
+}
+
+// deliverOnce makes a single dispatch attempt, used for email/FCM targets.
+func (h *notifyHub) deliverOnce(sub Subscription, submission db.Row) {
+	d := dispatcherFor(sub.Kind, h.webhook, h.email, h.fcm)
+	if d == nil {
+		return
+	}
+	err := d.Dispatch(context.Background(), sub, submission)
+	status := deliveryStatusSuccess
+	errMsg := ""
+	if err != nil {
+		status = deliveryStatusFailed
+		errMsg = err.Error()
+	}
+	h.recordDelivery(sub.ID, submission.ID, 1, status, errMsg)
+}
+
+// deliverWithRetry dispatches a webhook, retrying on failure per
+// backoffSchedule before giving up and recording a dead_letters row.
+func (h *notifyHub) deliverWithRetry(sub Subscription, submission db.Row) {
+	var lastErr error
+	for attempt := 1; attempt <= len(backoffSchedule)+1; attempt++ {
+		err := h.webhook.Dispatch(context.Background(), sub, submission)
+		if err == nil {
+			h.recordDelivery(sub.ID, submission.ID, attempt, deliveryStatusSuccess, "")
+			return
+		}
+		lastErr = err
+		h.recordDelivery(sub.ID, submission.ID, attempt, deliveryStatusFailed, err.Error())
+		if attempt <= len(backoffSchedule) {
+			time.Sleep(backoffSchedule[attempt-1])
+		}
+	}
+	h.recordDelivery(sub.ID, submission.ID, len(backoffSchedule)+1, deliveryStatusDeadLetter, lastErr.Error())
+	_, _ = h.db.Exec(
+		"INSERT INTO dead_letters (subscription_id, submission_id, last_error) VALUES (?, ?, ?)",
+		sub.ID, submission.ID, lastErr.Error(),
+	)
+}
+
+func (h *notifyHub) recordDelivery(subscriptionID, submissionID int64, attempt int, status, errMsg string) {
+	_, err := h.db.Exec(
+		"INSERT INTO deliveries (subscription_id, submission_id, attempt, status, error) VALUES (?, ?, ?, ?, ?)",
+		subscriptionID, submissionID, attempt, status, errMsg,
+	)
+	if err != nil {
+		log.Println("notifyHub: failed to record delivery:", err)
+	}
+}
+
+// createSubscriptionHandler handles POST /api/subscriptions.
+//
+// @Summary      Create a notification subscription
+// @Description  Requires a token with the admin scope.
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        subscription  body  object{kind=string,target=string,secret=string,filterExpr=string}  true  "Subscription to create (kind: webhook, email, or fcm)"
+// @Success      200  {object}  map[string]interface{}
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /api/subscriptions [post]
+func createSubscriptionHandler(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			var body struct {
+				Kind       string `json:"kind"`
+				Target     string `json:"target"`
+				Secret     string `json:"secret"`
+				FilterExpr string `json:"filterExpr"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				return nil, errBadRequest("invalid payload")
+			}
+			if body.Kind != subscriptionKindWebhook && body.Kind != subscriptionKindEmail && body.Kind != subscriptionKindFCM {
+				return nil, errBadRequest("kind must be one of webhook, email, fcm")
+			}
+			if body.Target == "" {
+				return nil, errBadRequest("target is required")
+			}
+			if _, err := matchesFilter(body.FilterExpr, db.Row{}); err != nil {
+				return nil, errBadRequest(err.Error())
+			}
+
+			res, err := conn.Exec(
+				"INSERT INTO subscriptions (kind, target, secret, filter_expr) VALUES (?, ?, ?, ?)",
+				body.Kind, body.Target, body.Secret, body.FilterExpr,
+			)
+			if err != nil {
+				return nil, errInternal("failed to save subscription")
+			}
+			id, _ := res.LastInsertId()
+			return gin.H{"id": id, "kind": body.Kind, "target": body.Target, "filterExpr": body.FilterExpr}, nil
+		})
+	}
+}
+
+// listDeliveriesHandler handles GET /api/subscriptions/:id/deliveries.
+//
+// @Summary      List delivery attempts for a subscription
+// @Description  Requires a token with the admin scope.
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id  path  int  true  "Subscription ID"
+// @Success      200  {array}  Delivery
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Router       /api/subscriptions/{id}/deliveries [get]
+func listDeliveriesHandler(conn *sql.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		Invoke(c, func() (interface{}, *HTTPError) {
+			id, err := parseID(c.Param("id"))
+			if err != nil {
+				return nil, errBadRequest("invalid id")
+			}
+
+			rows, err := conn.Query(
+				"SELECT id, subscription_id, submission_id, attempt, status, error, created_at FROM deliveries WHERE subscription_id = ? ORDER BY created_at DESC",
+				id,
+			)
+			if err != nil {
+				return nil, errInternal("failed to list deliveries")
+			}
+			defer rows.Close()
+
+			out := []Delivery{}
+			for rows.Next() {
+				var d Delivery
+				var errMsg sql.NullString
+				if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.SubmissionID, &d.Attempt, &d.Status, &errMsg, &d.CreatedAt); err != nil {
+					return nil, errInternal("failed to read deliveries")
+				}
+				d.Error = errMsg.String
+				out = append(out, d)
+			}
+			return out, nil
+		})
+	}
+}
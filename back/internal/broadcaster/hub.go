@@ -0,0 +1,241 @@
+// Package broadcaster implements multi-topic pub/sub with bounded per-topic
+// history replay and backpressure-aware fan-out, shared by every streaming
+// transport (SSE, chunked JSON, WebSocket).
+package broadcaster
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LegacySubmissionsTopic is the topic name backing the original single-firehose
+// submissions stream, now just another topic on the shared hub.
+const LegacySubmissionsTopic = "submissions"
+
+// Message is a single published message retained in a topic's replay buffer.
+type Message struct {
+	ID        int64           `json:"id"`
+	Topic     string          `json:"topic"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Topic holds the fan-out subscribers and bounded replay cache for a single topic.
+type Topic struct {
+	name    string
+	mu      sync.Mutex
+	nextID  int64
+	history *list.List // of *Message, oldest at Front
+
+	subMu sync.Mutex
+	subs  map[*Subscriber]struct{}
+}
+
+func newTopic(name string) *Topic {
+	return &Topic{
+		name:    name,
+		history: list.New(),
+		subs:    make(map[*Subscriber]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber on this topic.
+func (t *Topic) Subscribe(kind SubscriberKind) *Subscriber {
+	sub := newSubscriber(kind, t.name)
+	t.subMu.Lock()
+	t.subs[sub] = struct{}{}
+	t.subMu.Unlock()
+	return sub
+}
+
+// Unsubscribe removes a subscriber. Safe to call more than once.
+func (t *Topic) Unsubscribe(sub *Subscriber) {
+	t.subMu.Lock()
+	delete(t.subs, sub)
+	t.subMu.Unlock()
+}
+
+// SubscriberCount returns the number of live subscribers on this topic.
+func (t *Topic) SubscriberCount() int {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	return len(t.subs)
+}
+
+// QueueDepth sums the buffered message count across this topic's subscribers.
+func (t *Topic) QueueDepth() int {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	depth := 0
+	for sub := range t.subs {
+		depth += sub.QueueDepth()
+	}
+	return depth
+}
+
+// Since returns buffered messages newer than the given cursor, in publish
+// order. cursor == 0 returns the full retained history.
+func (t *Topic) Since(cursor int64) []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []Message
+	for e := t.history.Front(); e != nil; e = e.Next() {
+		m := e.Value.(*Message)
+		if m.ID > cursor {
+			out = append(out, *m)
+		}
+	}
+	return out
+}
+
+// SinceTime returns buffered messages published at or after the given time.
+func (t *Topic) SinceTime(ts time.Time) []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []Message
+	for e := t.history.Front(); e != nil; e = e.Next() {
+		m := e.Value.(*Message)
+		if !m.CreatedAt.Before(ts) {
+			out = append(out, *m)
+		}
+	}
+	return out
+}
+
+// Hub owns every topic, created lazily on first publish or subscribe.
+type Hub struct {
+	mu     sync.Mutex
+	topics map[string]*Topic
+	stats  *Stats
+
+	maxBufferSize int
+	maxBufferAge  time.Duration
+}
+
+// NewHub creates a Hub whose per-topic replay buffers are bounded by
+// maxBufferSize entries and maxBufferAge (0 disables the age bound).
+func NewHub(maxBufferSize int, maxBufferAge time.Duration) *Hub {
+	return &Hub{
+		topics:        make(map[string]*Topic),
+		stats:         newStats(),
+		maxBufferSize: maxBufferSize,
+		maxBufferAge:  maxBufferAge,
+	}
+}
+
+// Topic returns the named topic, creating it if this is the first reference.
+func (h *Hub) Topic(name string) *Topic {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	t, ok := h.topics[name]
+	if !ok {
+		t = newTopic(name)
+		h.topics[name] = t
+	}
+	return t
+}
+
+// Publish appends a message to the topic's history and fans it out to live subscribers.
+func (h *Hub) Publish(name string, data json.RawMessage) Message {
+	t := h.Topic(name)
+
+	t.mu.Lock()
+	t.nextID++
+	msg := Message{ID: t.nextID, Topic: name, Data: data, CreatedAt: time.Now()}
+	t.history.PushBack(&msg)
+	h.evictLocked(t)
+	t.mu.Unlock()
+
+	h.stats.recordMessage()
+
+	t.subMu.Lock()
+	for sub := range t.subs {
+		sub.deliver(msg, h.stats)
+	}
+	t.subMu.Unlock()
+
+	return msg
+}
+
+// evictLocked drops history entries past the configured count or age bound.
+// Caller must hold t.mu.
+func (h *Hub) evictLocked(t *Topic) {
+	for t.history.Len() > h.maxBufferSize {
+		t.history.Remove(t.history.Front())
+	}
+	if h.maxBufferAge <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-h.maxBufferAge)
+	for t.history.Len() > 0 {
+		front := t.history.Front().Value.(*Message)
+		if front.CreatedAt.After(cutoff) {
+			break
+		}
+		t.history.Remove(t.history.Front())
+	}
+}
+
+// StatsReport holds hub-wide counters plus active subscriber count and per-topic
+// queue depth.
+type StatsReport struct {
+	ActiveSubscribers  int
+	MessagesPerSecond  int64
+	MessagesTotal      int64
+	DropsTotal         int64
+	PerTopicQueueDepth map[string]int
+}
+
+// StatsSnapshot computes a point-in-time StatsReport across every topic.
+func (h *Hub) StatsSnapshot() StatsReport {
+	messagesTotal, dropsTotal, currentRate := h.stats.snapshot()
+
+	h.mu.Lock()
+	topics := make(map[string]*Topic, len(h.topics))
+	for name, t := range h.topics {
+		topics[name] = t
+	}
+	h.mu.Unlock()
+
+	activeSubscribers := 0
+	perTopicQueueDepth := make(map[string]int, len(topics))
+	for name, t := range topics {
+		activeSubscribers += t.SubscriberCount()
+		perTopicQueueDepth[name] = t.QueueDepth()
+	}
+
+	return StatsReport{
+		ActiveSubscribers:  activeSubscribers,
+		MessagesPerSecond:  currentRate,
+		MessagesTotal:      messagesTotal,
+		DropsTotal:         dropsTotal,
+		PerTopicQueueDepth: perTopicQueueDepth,
+	}
+}
+
+// ResolveSince interprets a ?since= query value against a topic and returns the
+// backlog to replay plus whether the caller should also enter live streaming
+// mode. Supported forms: "all" (full retained history), a per-topic message
+// ID, a unix millisecond timestamp, or empty (live only, no replay).
+func ResolveSince(t *Topic, since string) (replay []Message, live bool, err error) {
+	live = true
+	switch {
+	case since == "":
+		return nil, live, nil
+	case since == "all":
+		return t.Since(0), live, nil
+	default:
+		if n, convErr := strconv.ParseInt(since, 10, 64); convErr == nil {
+			if n > 1_000_000_000_000 {
+				// Large enough to be a millisecond timestamp rather than a message ID.
+				return t.SinceTime(time.UnixMilli(n)), live, nil
+			}
+			return t.Since(n), live, nil
+		}
+		return nil, live, fmt.Errorf("invalid since value: %q", since)
+	}
+}
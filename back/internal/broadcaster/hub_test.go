@@ -0,0 +1,54 @@
+package broadcaster
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestHubPublishDeliversToSubscriber(t *testing.T) {
+	h := NewHub(10, time.Hour)
+	sub := h.Topic("orders").Subscribe(SubscriberSSE)
+
+	h.Publish("orders", json.RawMessage(`{"ok":true}`))
+
+	select {
+	case msg := <-sub.Queue:
+		if msg.Topic != "orders" {
+			t.Fatalf("expected topic 'orders', got %q", msg.Topic)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for delivered message")
+	}
+}
+
+func TestHubReplayHonorsSinceCursor(t *testing.T) {
+	h := NewHub(10, time.Hour)
+	first := h.Publish("orders", json.RawMessage(`1`))
+	h.Publish("orders", json.RawMessage(`2`))
+
+	replay, live, err := ResolveSince(h.Topic("orders"), "")
+	if err != nil {
+		t.Fatalf("ResolveSince: %v", err)
+	}
+	if !live || len(replay) != 0 {
+		t.Fatalf("empty since should be live-only, got live=%v replay=%v", live, replay)
+	}
+
+	replay, _, err = ResolveSince(h.Topic("orders"), "all")
+	if err != nil {
+		t.Fatalf("ResolveSince(all): %v", err)
+	}
+	if len(replay) != 2 {
+		t.Fatalf("expected 2 replayed messages, got %d", len(replay))
+	}
+
+	replay, _, err = ResolveSince(h.Topic("orders"), strconv.FormatInt(first.ID, 10))
+	if err != nil {
+		t.Fatalf("ResolveSince(cursor): %v", err)
+	}
+	if len(replay) != 1 {
+		t.Fatalf("expected 1 message after first's cursor, got %d", len(replay))
+	}
+}
@@ -0,0 +1,136 @@
+package broadcaster
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SubscriberKind distinguishes the transport a subscriber is attached to,
+// purely for stats/labeling purposes - delivery and lag handling are
+// transport-agnostic.
+type SubscriberKind string
+
+const (
+	SubscriberSSE SubscriberKind = "sse"
+	SubscriberWS  SubscriberKind = "ws"
+)
+
+// laggedDisconnectThreshold is how many consecutive missed messages a
+// subscriber can accumulate before it is force-disconnected rather than just
+// warned.
+const laggedDisconnectThreshold = 50
+
+type ControlEventKind string
+
+const (
+	ControlLagged ControlEventKind = "lagged"
+	ControlClose  ControlEventKind = "close"
+)
+
+// ControlEvent is an out-of-band signal to a subscriber's consumer loop,
+// delivered alongside (not through) the regular message queue so it can't be
+// starved by a full queue.
+type ControlEvent struct {
+	Kind   ControlEventKind
+	Missed int
+}
+
+// Subscriber is the common unit of fan-out shared by SSE and WebSocket
+// clients: a bounded queue of messages plus a 1-slot control channel for
+// lag/close signals. When the queue fills, deliver marks the subscriber
+// lagging instead of silently dropping.
+type Subscriber struct {
+	Kind    SubscriberKind
+	Topic   string
+	Queue   chan Message
+	Control chan ControlEvent
+
+	mu     sync.Mutex
+	missed int
+}
+
+func newSubscriber(kind SubscriberKind, topicName string) *Subscriber {
+	return &Subscriber{
+		Kind:    kind,
+		Topic:   topicName,
+		Queue:   make(chan Message, 32),
+		Control: make(chan ControlEvent, 1),
+	}
+}
+
+// deliver attempts to enqueue msg without blocking. On success any prior lag is
+// cleared. On a full queue it records a drop and either warns the subscriber
+// (still under threshold) or asks its consumer loop to close the connection.
+func (s *Subscriber) deliver(msg Message, stats *Stats) {
+	select {
+	case s.Queue <- msg:
+		s.mu.Lock()
+		s.missed = 0
+		s.mu.Unlock()
+		return
+	default:
+	}
+
+	stats.recordDrop()
+	s.mu.Lock()
+	s.missed++
+	missed := s.missed
+	s.mu.Unlock()
+
+	if missed > laggedDisconnectThreshold {
+		s.notify(ControlEvent{Kind: ControlClose})
+		return
+	}
+	s.notify(ControlEvent{Kind: ControlLagged, Missed: missed})
+}
+
+// notify posts a control event, dropping it if one is already pending - the
+// consumer will see the newer state (lag count, or close) next time around.
+func (s *Subscriber) notify(ev ControlEvent) {
+	select {
+	case s.Control <- ev:
+	default:
+	}
+}
+
+// QueueDepth reports how many messages are currently buffered for this subscriber.
+func (s *Subscriber) QueueDepth() int {
+	return len(s.Queue)
+}
+
+// Stats tracks publish/drop counters and a rolling messages/sec rate across
+// every topic in a Hub.
+type Stats struct {
+	messagesTotal int64
+	dropsTotal    int64
+	rateWindow    int64
+	currentRate   int64
+}
+
+func newStats() *Stats {
+	s := &Stats{}
+	go s.runRateTicker()
+	return s
+}
+
+func (s *Stats) recordMessage() {
+	atomic.AddInt64(&s.messagesTotal, 1)
+	atomic.AddInt64(&s.rateWindow, 1)
+}
+
+func (s *Stats) recordDrop() {
+	atomic.AddInt64(&s.dropsTotal, 1)
+}
+
+func (s *Stats) runRateTicker() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		atomic.StoreInt64(&s.currentRate, atomic.SwapInt64(&s.rateWindow, 0))
+	}
+}
+
+func (s *Stats) snapshot() (messagesTotal, dropsTotal, currentRate int64) {
+	return atomic.LoadInt64(&s.messagesTotal), atomic.LoadInt64(&s.dropsTotal), atomic.LoadInt64(&s.currentRate)
+}
@@ -0,0 +1,121 @@
+// Package config centralizes environment parsing into a single Options struct
+// so the rest of the service never calls os.Getenv directly.
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Options holds every environment-tunable setting for the service.
+type Options struct {
+	SQLitePath     string
+	Port           string
+	AllowedOrigins map[string]bool
+
+	TopicBufferSize int
+	TopicBufferAge  time.Duration
+
+	SubmitRatePerMin float64
+	SubmitBurst      int
+	MaxSSEPerVisitor int
+
+	NotifierWorkers int
+	SMTPAddr        string
+	SMTPFrom        string
+	SMTPUsername    string
+	SMTPPassword    string
+	FCMCredsFile    string
+
+	AdminBootstrapToken string
+}
+
+// Load reads Options from the process environment, applying the same
+// defaults the service has always shipped with.
+func Load() Options {
+	return Options{
+		SQLitePath:     getEnv("SQLITE_PATH", "./app.db"),
+		Port:           getEnv("PORT", "8080"),
+		AllowedOrigins: parseAllowedOrigins(getEnv("ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000")),
+
+		TopicBufferSize: getEnvInt("TOPIC_BUFFER_SIZE", 200),
+		TopicBufferAge:  getEnvDuration("TOPIC_BUFFER_AGE", time.Hour),
+
+		SubmitRatePerMin: getEnvFloat("SUBMIT_RATE_PER_MIN", 10),
+		SubmitBurst:      getEnvInt("SUBMIT_BURST", 20),
+		MaxSSEPerVisitor: getEnvInt("MAX_SSE_PER_VISITOR", 5),
+
+		NotifierWorkers: getEnvInt("NOTIFIER_WORKERS", 4),
+		SMTPAddr:        os.Getenv("SMTP_ADDR"),
+		SMTPFrom:        getEnv("SMTP_FROM", "notifications@localhost"),
+		SMTPUsername:    os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:    os.Getenv("SMTP_PASSWORD"),
+		FCMCredsFile:    os.Getenv("FCM_CREDENTIALS_FILE"),
+
+		AdminBootstrapToken: os.Getenv("ADMIN_BOOTSTRAP_TOKEN"),
+	}
+}
+
+// Addr returns Port normalized to a net/http listen address (":8080").
+func (o Options) Addr() string {
+	if strings.HasPrefix(o.Port, ":") {
+		return o.Port
+	}
+	return ":" + o.Port
+}
+
+func getEnv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+func getEnvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+func getEnvFloat(key string, def float64) float64 {
+	if v := os.Getenv(key); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// parseAllowedOrigins parses a comma-separated list into a set.
+func parseAllowedOrigins(csv string) map[string]bool {
+	out := make(map[string]bool)
+	for _, p := range strings.Split(csv, ",") {
+		v := NormalizeOrigin(strings.TrimSpace(p))
+		if v != "" {
+			out[v] = true
+		}
+	}
+	if len(out) == 0 {
+		out["*"] = true
+	}
+	return out
+}
+
+// NormalizeOrigin ensures origins are compared without trailing slashes.
+func NormalizeOrigin(origin string) string {
+	return strings.TrimRight(origin, "/")
+}
@@ -0,0 +1,81 @@
+// Package db owns the SQLite connection and schema migrations. Table-specific
+// query helpers live alongside it as typed stores (see SubmissionStore).
+package db
+
+import (
+	"database/sql"
+	"embed"
+	"sort"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Open connects to the SQLite database at path and applies every embedded
+// migration in filename order. Migrations are idempotent (CREATE TABLE IF NOT
+// EXISTS, best-effort ALTER TABLE) so this is safe to call on every boot.
+func Open(path string) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(conn); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func migrate(conn *sql.DB) error {
+	entries, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		contents, err := migrationFiles.ReadFile("migrations/" + name)
+		if err != nil {
+			return err
+		}
+		for _, stmt := range splitStatements(string(contents)) {
+			if _, err := conn.Exec(stmt); err != nil {
+				// Legacy ALTER TABLE ADD COLUMN migrations re-run on every boot and
+				// fail once the column already exists; that failure is expected.
+				if strings.Contains(name, "legacy") {
+					continue
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// splitStatements does a naive split on statement-terminating semicolons and
+// drops "--" comment lines, which is sufficient for this package's DDL-only
+// migration files.
+func splitStatements(sqlText string) []string {
+	var out []string
+	for _, part := range strings.Split(sqlText, ";") {
+		var lines []string
+		for _, line := range strings.Split(part, "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				lines = append(lines, line)
+			}
+		}
+		stmt := strings.TrimSpace(strings.Join(lines, "\n"))
+		if stmt == "" {
+			continue
+		}
+		out = append(out, stmt)
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Submission is the set of fields a client sends in on a new form submission.
+type Submission struct {
+	Name    string
+	Email   string
+	Message string
+	// ClientSubmitAt is the client-reported submit timestamp in epoch milliseconds.
+	ClientSubmitAt int64
+}
+
+// Row is a submission as stored, including server-computed fields and the
+// latency metrics reported back by clients after display.
+type Row struct {
+	ID                      int64
+	Name                    string
+	Email                   string
+	Message                 string
+	Timestamp               string
+	ClientSubmitAtMs        sql.NullInt64
+	ServerBroadcastAtMs     sql.NullInt64
+	ClientSubmitToServerMs  sql.NullInt64
+	ClientServerToDisplayMs sql.NullInt64
+	ClientSubmitToDisplayMs sql.NullInt64
+}
+
+// SubmissionStore wraps the submissions table with the queries the API layer needs.
+type SubmissionStore struct {
+	db *sql.DB
+}
+
+func NewSubmissionStore(conn *sql.DB) *SubmissionStore {
+	return &SubmissionStore{db: conn}
+}
+
+// Insert saves a new submission and returns its assigned ID.
+func (s *SubmissionStore) Insert(sub Submission) (int64, error) {
+	res, err := s.db.Exec(
+		"INSERT INTO submissions (name, email, message, client_submit_at_ms) VALUES (?, ?, ?, ?)",
+		sub.Name, sub.Email, sub.Message, sub.ClientSubmitAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// SetServerBroadcastAt records when the server fanned a submission out to subscribers.
+func (s *SubmissionStore) SetServerBroadcastAt(id, unixMs int64) error {
+	_, err := s.db.Exec("UPDATE submissions SET server_broadcast_at_ms = ? WHERE id = ?", unixMs, id)
+	return err
+}
+
+// List returns the most recent submissions, newest first, up to limit rows.
+func (s *SubmissionStore) List(limit int) ([]Row, error) {
+	rows, err := s.db.Query(`SELECT id, name, email, message, timestamp,
+		client_submit_at_ms, server_broadcast_at_ms,
+		client_submit_to_server_ms, client_server_to_display_ms, client_submit_to_display_ms
+		FROM submissions ORDER BY timestamp DESC LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Row
+	for rows.Next() {
+		var r Row
+		if err := rows.Scan(&r.ID, &r.Name, &r.Email, &r.Message, &r.Timestamp,
+			&r.ClientSubmitAtMs, &r.ServerBroadcastAtMs,
+			&r.ClientSubmitToServerMs, &r.ClientServerToDisplayMs, &r.ClientSubmitToDisplayMs); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// Get returns a single submission by ID, formatted like List's rows, for
+// fan-out to notification dispatchers.
+func (s *SubmissionStore) Get(id int64) (Row, error) {
+	var r Row
+	err := s.db.QueryRow(`SELECT id, name, email, message, timestamp,
+		client_submit_at_ms, server_broadcast_at_ms,
+		client_submit_to_server_ms, client_server_to_display_ms, client_submit_to_display_ms
+		FROM submissions WHERE id = ?`, id).Scan(
+		&r.ID, &r.Name, &r.Email, &r.Message, &r.Timestamp,
+		&r.ClientSubmitAtMs, &r.ServerBroadcastAtMs,
+		&r.ClientSubmitToServerMs, &r.ClientServerToDisplayMs, &r.ClientSubmitToDisplayMs,
+	)
+	return r, err
+}
+
+// LatencyUpdate carries whichever client-reported latency fields were present
+// on a POST /api/submissions/:id/latency request; nil fields are left unset.
+type LatencyUpdate struct {
+	SubmitToServerMs  *int64
+	ServerToDisplayMs *int64
+	SubmitToDisplayMs *int64
+}
+
+// UpdateLatency applies a partial latency update to one submission. Returns
+// false if the update had no fields set.
+func (s *SubmissionStore) UpdateLatency(id int64, u LatencyUpdate) (bool, error) {
+	sets := []string{}
+	args := []interface{}{}
+	if u.SubmitToServerMs != nil {
+		sets = append(sets, "client_submit_to_server_ms = ?")
+		args = append(args, *u.SubmitToServerMs)
+	}
+	if u.ServerToDisplayMs != nil {
+		sets = append(sets, "client_server_to_display_ms = ?")
+		args = append(args, *u.ServerToDisplayMs)
+	}
+	if u.SubmitToDisplayMs != nil {
+		sets = append(sets, "client_submit_to_display_ms = ?")
+		args = append(args, *u.SubmitToDisplayMs)
+	}
+	if len(sets) == 0 {
+		return false, nil
+	}
+	args = append(args, id)
+	q := "UPDATE submissions SET " + strings.Join(sets, ", ") + " WHERE id = ?"
+	_, err := s.db.Exec(q, args...)
+	return true, err
+}
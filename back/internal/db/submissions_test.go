@@ -0,0 +1,60 @@
+package db
+
+import "testing"
+
+func TestSubmissionStoreInsertAndList(t *testing.T) {
+	conn, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer conn.Close()
+
+	store := NewSubmissionStore(conn)
+
+	id, err := store.Insert(Submission{Name: "Ada", Email: "ada@example.com", Message: "hello"})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+	if id == 0 {
+		t.Fatalf("expected a non-zero inserted ID")
+	}
+
+	rows, err := store.List(10)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Name != "Ada" {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+}
+
+func TestSubmissionStoreUpdateLatency(t *testing.T) {
+	conn, err := Open(":memory:")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer conn.Close()
+
+	store := NewSubmissionStore(conn)
+	id, err := store.Insert(Submission{Name: "Grace", Email: "grace@example.com", Message: "hi"})
+	if err != nil {
+		t.Fatalf("Insert: %v", err)
+	}
+
+	submitToServer := int64(42)
+	applied, err := store.UpdateLatency(id, LatencyUpdate{SubmitToServerMs: &submitToServer})
+	if err != nil {
+		t.Fatalf("UpdateLatency: %v", err)
+	}
+	if !applied {
+		t.Fatalf("expected UpdateLatency to report fields were applied")
+	}
+
+	row, err := store.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !row.ClientSubmitToServerMs.Valid || row.ClientSubmitToServerMs.Int64 != 42 {
+		t.Fatalf("latency not applied: %+v", row)
+	}
+}